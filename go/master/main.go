@@ -16,23 +16,171 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	logger "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/controller"
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/lock"
 	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/server"
 )
 
+func newLocker(backend, namespace, jobName, etcdEndpoints string) lock.Locker {
+	switch backend {
+	case "etcd":
+		endpoints := strings.Split(etcdEndpoints, ",")
+		locker, err := lock.NewEtcdLocker(endpoints, namespace, jobName)
+		if err != nil {
+			logger.Fatalf("failed to create etcd lock backend: %v", err)
+		}
+		return locker
+	case "memory":
+		return lock.NewMemoryLocker()
+	default:
+		logger.Fatalf("unknown lock backend %q, expected \"etcd\" or \"memory\"", backend)
+		return nil
+	}
+}
+
+// newKubeConfig loads the kubeconfig file at the given path when one is set
+// (e.g. for local development), and the in-cluster config otherwise.
+func newKubeConfig(kubeconfig string) (*rest.Config, error) {
+	if kubeconfig != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	return rest.InClusterConfig()
+}
+
+// newEventRecorder builds a recorder that emits Events against resources in
+// namespace, attributed to the dlock-master component.
+func newEventRecorder(clientset kubernetes.Interface, namespace string) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events(namespace)})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "dlock-master"})
+}
+
 func main() {
 	var namespace string
 	var jobName string
 	var port int
+	var backend string
+	var etcdEndpoints string
+	var kubeconfig string
+	var debugDeadlock bool
+	var deadlockThreshold time.Duration
+	var deadlockMaxWaiters int
+	var metricsEnabled bool
+	var otlpEndpoint string
+	var webhookConfig string
+	var servedVersions string
 
 	flag.StringVar(&namespace, "namespace", "default", "The name of the Kubernetes namespace.")
 	flag.StringVar(&jobName, "job_name", "", "The dlock/elasticjob name.")
 	flag.IntVar(&port, "port", 8080, "The port which the master service binds to.")
+	flag.StringVar(&backend, "backend", "memory", "The lock backend to use: \"etcd\" or \"memory\".")
+	flag.StringVar(&etcdEndpoints, "etcd-endpoints", "127.0.0.1:2379", "Comma-separated etcd endpoints, used when -backend=etcd.")
+	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig file; leave empty to use the in-cluster config.")
+	flag.BoolVar(&debugDeadlock, "debug-deadlock", false, "Enable the background monitor that warns about long-held locks and large acquire-wait queues, and exposes GET /debug/locks.")
+	flag.DurationVar(&deadlockThreshold, "debug-deadlock-threshold", 30*time.Second, "How long a lock may be held before -debug-deadlock logs a warning.")
+	flag.IntVar(&deadlockMaxWaiters, "debug-deadlock-max-waiters", 5, "How many callers may be queued acquiring the same key before -debug-deadlock logs a warning.")
+	flag.BoolVar(&metricsEnabled, "metrics", false, "Expose GET /metrics in Prometheus format.")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "", "OTLP/gRPC collector endpoint for request tracing; leave empty to disable tracing.")
+	flag.StringVar(&webhookConfig, "webhook-config", "", "Path to a JSON file of webhooks to register at startup; leave empty to register none.")
+	flag.StringVar(&servedVersions, "served-versions", "v1alpha1,v1beta1", "Comma-separated list of /apis/dlock.dlrover.io versions to serve, so older versions can be disabled during a rolling upgrade.")
+	flag.Parse()
+
+	locker := newLocker(backend, namespace, jobName, etcdEndpoints)
+	defer locker.Close()
+
+	var monitor *server.DeadlockMonitor
 	router := server.NewRouter()
+	if debugDeadlock {
+		monitor = server.NewDeadlockMonitor(deadlockThreshold, deadlockMaxWaiters)
+		server.RegisterDebugRoutes(router, monitor)
+
+		stopCh := make(chan struct{})
+		go monitor.Start(stopCh)
+		defer close(stopCh)
+	}
+
+	if otlpEndpoint != "" {
+		provider, err := server.InitTracing(context.Background(), otlpEndpoint)
+		if err != nil {
+			logger.Fatalf("failed to initialize tracing: %v", err)
+		}
+		defer provider.Shutdown(context.Background())
+		router.Use(server.TracingMiddleware())
+	}
+
+	lockHandler := server.NewLockHandler(locker, namespace, jobName, monitor)
+
+	if metricsEnabled {
+		server.RegisterMetricsRoute(router)
+		lockHandler.AddObserver(server.NewMetricsRecorder())
+	}
+
+	webhooks := server.NewWebhookRegistry()
+	if webhookConfig != "" {
+		hooks, err := server.LoadWebhookConfig(webhookConfig)
+		if err != nil {
+			logger.Fatalf("failed to load webhook config: %v", err)
+		}
+		for _, hook := range hooks {
+			webhooks.Register(hook)
+		}
+	}
+	server.RegisterWebhookRoutes(router, webhooks)
+	lockHandler.AddObserver(webhooks)
+
+	server.RegisterLockRoutes(router, lockHandler)
+	server.NewVersionedAPI(lockHandler, strings.Split(servedVersions, ",")).RegisterRoutes(router)
+
+	if restConfig, err := newKubeConfig(kubeconfig); err != nil {
+		logger.Warnf("no Kubernetes config available, annotation-driven lock registration is disabled: %v", err)
+	} else {
+		dynamicClient, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			logger.Fatalf("failed to create Kubernetes dynamic client: %v", err)
+		}
+		clientset, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			logger.Fatalf("failed to create Kubernetes clientset: %v", err)
+		}
+
+		ctl := controller.New(dynamicClient, namespace, locker, newEventRecorder(clientset, namespace))
+		server.RegisterRegistrationsRoute(router, ctl.Registry())
+
+		stopCh := make(chan struct{})
+		go ctl.Run(stopCh)
+		defer close(stopCh)
+	}
+
+	// Release any locks we hold on process exit so that a graceful restart
+	// does not leave stale holders blocking other workers until their TTL
+	// expires.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		logger.Info("shutting down, releasing held locks")
+		locker.Close()
+		os.Exit(0)
+	}()
 
 	// Listen and serve on defined port
 	logger.Infof("The master starts with namespece %s, jobName %s, port %d", namespace, jobName, port)
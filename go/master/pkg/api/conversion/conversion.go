@@ -0,0 +1,43 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion maps the per-version request/response types in
+// v1alpha1 and v1beta1 to and from the internal api.Lock hub type, following
+// the kube-style ConvertTo/ConvertFrom pattern. It lets the HTTP layer work
+// against api.Lock regardless of which version a request or response is
+// serialized as.
+package conversion
+
+import "github.com/intelligent-machine-learning/dlrover/go/master/pkg/api"
+
+// Convertible is implemented by every versioned request/response type.
+type Convertible interface {
+	ConvertTo(hub *api.Lock) error
+	ConvertFrom(hub *api.Lock) error
+}
+
+// ToHub converts src into the internal hub representation.
+func ToHub(src Convertible) (*api.Lock, error) {
+	hub := &api.Lock{}
+	if err := src.ConvertTo(hub); err != nil {
+		return nil, err
+	}
+	return hub, nil
+}
+
+// FromHub populates dst from hub.
+func FromHub(hub *api.Lock, dst Convertible) error {
+	return dst.ConvertFrom(hub)
+}
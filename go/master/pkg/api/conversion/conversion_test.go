@@ -0,0 +1,71 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/api/v1alpha1"
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/api/v1beta1"
+)
+
+func TestV1alpha1RoundTrip(t *testing.T) {
+	req := &v1alpha1.AcquireRequest{TTLSeconds: 30, JobName: "train"}
+
+	hub, err := ToHub(req)
+	if err != nil {
+		t.Fatalf("ToHub: %v", err)
+	}
+	if hub.TTL != 30*time.Second {
+		t.Fatalf("expected TTL 30s, got %v", hub.TTL)
+	}
+
+	var out v1alpha1.AcquireRequest
+	if err := FromHub(hub, &out); err != nil {
+		t.Fatalf("FromHub: %v", err)
+	}
+	if out != *req {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", out, *req)
+	}
+}
+
+func TestV1alpha1ToV1beta1(t *testing.T) {
+	src := &v1alpha1.LockResponse{Key: "job-a", FencingToken: 42, Holder: "holder-1"}
+
+	hub, err := ToHub(src)
+	if err != nil {
+		t.Fatalf("ToHub: %v", err)
+	}
+
+	var dst v1beta1.LockResponse
+	if err := FromHub(hub, &dst); err != nil {
+		t.Fatalf("FromHub: %v", err)
+	}
+	if dst.FencingToken != "42" {
+		t.Fatalf("expected fencing token \"42\", got %q", dst.FencingToken)
+	}
+	if dst.Key != "job-a" || dst.Holder != "holder-1" {
+		t.Fatalf("unexpected conversion result: %+v", dst)
+	}
+}
+
+func TestV1beta1InvalidTTL(t *testing.T) {
+	req := &v1beta1.AcquireRequest{TTL: "not-a-duration"}
+	if _, err := ToHub(req); err == nil {
+		t.Fatalf("expected an error converting an invalid ttl")
+	}
+}
@@ -0,0 +1,32 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package api holds the internal ("hub") representation of the lock
+// resource that every served API version converts to and from, so that a
+// breaking change to lock semantics only needs a new version package rather
+// than a change to every existing client.
+package api
+
+import "time"
+
+// Lock is the hub type every versioned Lock representation (v1alpha1,
+// v1beta1, ...) converts through. It is never serialized directly.
+type Lock struct {
+	Key          string
+	FencingToken int64
+	Holder       string
+	JobName      string
+	TTL          time.Duration
+}
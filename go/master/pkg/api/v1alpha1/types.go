@@ -0,0 +1,73 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 is the original, unstable shape of the dlock.dlrover.io
+// lock API: /apis/dlock.dlrover.io/v1alpha1.
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/api"
+)
+
+// GroupVersion is this package's served version string.
+const GroupVersion = "v1alpha1"
+
+// AcquireRequest is the request body for POST .../locks/:key.
+type AcquireRequest struct {
+	TTLSeconds int    `json:"ttlSeconds"`
+	JobName    string `json:"jobName"`
+}
+
+// ConvertTo populates hub from r.
+func (r *AcquireRequest) ConvertTo(hub *api.Lock) error {
+	hub.JobName = r.JobName
+	if r.TTLSeconds > 0 {
+		hub.TTL = time.Duration(r.TTLSeconds) * time.Second
+	}
+	return nil
+}
+
+// ConvertFrom populates r from hub.
+func (r *AcquireRequest) ConvertFrom(hub *api.Lock) error {
+	r.JobName = hub.JobName
+	r.TTLSeconds = int(hub.TTL.Seconds())
+	return nil
+}
+
+// LockResponse is the response body for every
+// /apis/dlock.dlrover.io/v1alpha1/locks/:key method.
+type LockResponse struct {
+	Key          string `json:"key"`
+	FencingToken int64  `json:"fencingToken"`
+	Holder       string `json:"holder"`
+}
+
+// ConvertTo populates hub from r.
+func (r *LockResponse) ConvertTo(hub *api.Lock) error {
+	hub.Key = r.Key
+	hub.FencingToken = r.FencingToken
+	hub.Holder = r.Holder
+	return nil
+}
+
+// ConvertFrom populates r from hub.
+func (r *LockResponse) ConvertFrom(hub *api.Lock) error {
+	r.Key = hub.Key
+	r.FencingToken = hub.FencingToken
+	r.Holder = hub.Holder
+	return nil
+}
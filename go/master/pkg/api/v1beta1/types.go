@@ -0,0 +1,89 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 is the dlock.dlrover.io lock API after its first breaking
+// revision: /apis/dlock.dlrover.io/v1beta1. Relative to v1alpha1 it accepts
+// a Go duration string instead of a bare integer of seconds, and reports the
+// fencing token as a string so that large values survive round-tripping
+// through JSON numbers in client languages that use float64.
+package v1beta1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/api"
+)
+
+// GroupVersion is this package's served version string.
+const GroupVersion = "v1beta1"
+
+// AcquireRequest is the request body for POST .../locks/:key.
+type AcquireRequest struct {
+	TTL     string `json:"ttl"`
+	JobName string `json:"jobName"`
+}
+
+// ConvertTo populates hub from r.
+func (r *AcquireRequest) ConvertTo(hub *api.Lock) error {
+	hub.JobName = r.JobName
+	if r.TTL == "" {
+		return nil
+	}
+	ttl, err := time.ParseDuration(r.TTL)
+	if err != nil {
+		return fmt.Errorf("v1beta1: invalid ttl %q: %w", r.TTL, err)
+	}
+	hub.TTL = ttl
+	return nil
+}
+
+// ConvertFrom populates r from hub.
+func (r *AcquireRequest) ConvertFrom(hub *api.Lock) error {
+	r.JobName = hub.JobName
+	r.TTL = hub.TTL.String()
+	return nil
+}
+
+// LockResponse is the response body for every
+// /apis/dlock.dlrover.io/v1beta1/locks/:key method.
+type LockResponse struct {
+	Key          string `json:"key"`
+	FencingToken string `json:"fencingToken"`
+	Holder       string `json:"holder"`
+}
+
+// ConvertTo populates hub from r.
+func (r *LockResponse) ConvertTo(hub *api.Lock) error {
+	hub.Key = r.Key
+	hub.Holder = r.Holder
+	if r.FencingToken == "" {
+		return nil
+	}
+	var token int64
+	if _, err := fmt.Sscanf(r.FencingToken, "%d", &token); err != nil {
+		return fmt.Errorf("v1beta1: invalid fencingToken %q: %w", r.FencingToken, err)
+	}
+	hub.FencingToken = token
+	return nil
+}
+
+// ConvertFrom populates r from hub.
+func (r *LockResponse) ConvertFrom(hub *api.Lock) error {
+	r.Key = hub.Key
+	r.FencingToken = fmt.Sprintf("%d", hub.FencingToken)
+	r.Holder = hub.Holder
+	return nil
+}
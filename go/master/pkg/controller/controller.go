@@ -0,0 +1,221 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controller watches ElasticJob, Pod and Service resources and
+// auto-registers the lock keys named in their dlock.dlrover.io annotations,
+// so workers don't have to call the master's HTTP API themselves.
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/lock"
+)
+
+// AnnotationLockKeys lists the comma-separated lock keys a resource wants
+// registered on its behalf, e.g. "job-a,shard-0".
+const AnnotationLockKeys = "dlock.dlrover.io/lock-keys"
+
+// AnnotationTTL is the lock TTL to use for the keys in AnnotationLockKeys,
+// parsed with time.ParseDuration. It defaults to 30s when absent.
+const AnnotationTTL = "dlock.dlrover.io/ttl"
+
+const defaultTTL = 30 * time.Second
+
+var elasticJobGVR = schema.GroupVersionResource{
+	Group:    "elastic.iml.github.io",
+	Version:  "v1alpha1",
+	Resource: "elasticjobs",
+}
+
+var podGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+var serviceGVR = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+
+// Controller auto-registers lock keys found on annotated Kubernetes
+// resources with a lock.Locker backend, and garbage-collects them when the
+// owning resource is deleted.
+type Controller struct {
+	namespace string
+	locker    lock.Locker
+	recorder  record.EventRecorder
+	registry  *Registry
+
+	informerFactory dynamicinformer.DynamicSharedInformerFactory
+}
+
+// New builds a Controller that watches namespace for ElasticJob, Pod and
+// Service resources.
+func New(dynamicClient dynamic.Interface, namespace string, locker lock.Locker, recorder record.EventRecorder) *Controller {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 30*time.Second, namespace, nil)
+
+	return &Controller{
+		namespace:       namespace,
+		locker:          locker,
+		recorder:        recorder,
+		registry:        NewRegistry(),
+		informerFactory: factory,
+	}
+}
+
+// Registry returns the set of currently-registered lock keys, for the
+// GET /v1/registrations endpoint.
+func (c *Controller) Registry() *Registry {
+	return c.registry
+}
+
+// Run starts the underlying informers and blocks until stopCh is closed.
+func (c *Controller) Run(stopCh <-chan struct{}) {
+	for _, gvr := range []schema.GroupVersionResource{elasticJobGVR, podGVR, serviceGVR} {
+		informer := c.informerFactory.ForResource(gvr).Informer()
+		informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.onAddOrUpdate(obj) },
+			UpdateFunc: func(_, obj interface{}) { c.onAddOrUpdate(obj) },
+			DeleteFunc: c.onDelete,
+		})
+	}
+
+	c.informerFactory.Start(stopCh)
+	c.informerFactory.WaitForCacheSync(stopCh)
+	<-stopCh
+}
+
+func (c *Controller) onAddOrUpdate(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	keys := lockKeysOf(u)
+	if len(keys) == 0 {
+		return
+	}
+	ttl := ttlOf(u)
+
+	ref := objectReference(u)
+	for _, key := range keys {
+		// TryMarkPending records the in-flight claim synchronously, before
+		// acquireFor's (possibly long-blocking, for the etcd backend)
+		// Acquire call starts. Without this, every 30s informer resync
+		// would pass the Has check again for a key whose Acquire hasn't
+		// returned yet, spawning another goroutine blocked on the same
+		// contended key.
+		if !c.registry.TryMarkPending(key, u.GetUID()) {
+			continue
+		}
+		go c.acquireFor(u, ref, key, ttl)
+	}
+}
+
+func (c *Controller) acquireFor(u *unstructured.Unstructured, ref *corev1.ObjectReference, key string, ttl time.Duration) {
+	token, lostCh, err := c.locker.Acquire(context.Background(), key, ttl)
+	if err != nil {
+		c.registry.ClearPending(key, u.GetUID())
+		logger.Warnf("controller: failed to acquire lock %q for %s/%s: %v", key, u.GetNamespace(), u.GetName(), err)
+		if c.recorder != nil && ref != nil {
+			c.recorder.Eventf(ref, corev1.EventTypeWarning, "LockAcquireFailed", "failed to acquire dlock key %q: %v", key, err)
+		}
+		return
+	}
+
+	c.registry.Put(key, u.GetUID(), u.GetNamespace(), u.GetName(), token)
+	if c.recorder != nil && ref != nil {
+		c.recorder.Eventf(ref, corev1.EventTypeNormal, "LockAcquired", "acquired dlock key %q (fencing token %d)", key, token.FencingToken)
+	}
+
+	ev, ok := <-lostCh
+	if !ok {
+		return
+	}
+	logger.Warnf("controller: lock %q lost for %s/%s: reason=%v", key, u.GetNamespace(), u.GetName(), ev.Reason)
+	c.registry.Delete(key, u.GetUID())
+	if c.recorder != nil && ref != nil {
+		c.recorder.Eventf(ref, corev1.EventTypeWarning, "LockLost", "lost dlock key %q", key)
+	}
+}
+
+func (c *Controller) onDelete(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			u, ok = tombstone.Obj.(*unstructured.Unstructured)
+			if !ok {
+				return
+			}
+		} else {
+			return
+		}
+	}
+
+	ref := objectReference(u)
+	for _, reg := range c.registry.ForOwner(u.GetUID()) {
+		if err := c.locker.Release(context.Background(), reg.Token); err != nil {
+			logger.Warnf("controller: failed to release lock %q on delete of %s/%s: %v", reg.Key, u.GetNamespace(), u.GetName(), err)
+		}
+		c.registry.Delete(reg.Key, u.GetUID())
+		if c.recorder != nil && ref != nil {
+			c.recorder.Eventf(ref, corev1.EventTypeNormal, "LockReleased", "released dlock key %q on deletion", reg.Key)
+		}
+	}
+}
+
+func lockKeysOf(u *unstructured.Unstructured) []string {
+	raw, ok := u.GetAnnotations()[AnnotationLockKeys]
+	if !ok || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	keys := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			keys = append(keys, p)
+		}
+	}
+	return keys
+}
+
+func ttlOf(u *unstructured.Unstructured) time.Duration {
+	raw, ok := u.GetAnnotations()[AnnotationTTL]
+	if !ok {
+		return defaultTTL
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		logger.Warnf("controller: invalid %s annotation %q on %s/%s, using default", AnnotationTTL, raw, u.GetNamespace(), u.GetName())
+		return defaultTTL
+	}
+	return d
+}
+
+func objectReference(u *unstructured.Unstructured) *corev1.ObjectReference {
+	gvk := u.GroupVersionKind()
+	return &corev1.ObjectReference{
+		Kind:       gvk.Kind,
+		APIVersion: gvk.GroupVersion().String(),
+		Namespace:  u.GetNamespace(),
+		Name:       u.GetName(),
+		UID:        u.GetUID(),
+	}
+}
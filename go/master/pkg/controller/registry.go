@@ -0,0 +1,156 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/lock"
+)
+
+// Registration records that a lock key was auto-registered on behalf of a
+// Kubernetes resource.
+type Registration struct {
+	Key       string     `json:"key"`
+	Namespace string     `json:"namespace"`
+	Owner     string     `json:"owner"`
+	OwnerUID  types.UID  `json:"owner_uid"`
+	Token     *lock.Token `json:"-"`
+}
+
+// Registry is the thread-safe set of lock keys currently auto-registered by
+// the controller, keyed by (lock key, owner UID) so the same key can be
+// registered by more than one resource without clobbering.
+type Registry struct {
+	mu      sync.RWMutex
+	items   map[string]map[types.UID]*Registration
+	pending map[string]map[types.UID]bool
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		items:   make(map[string]map[types.UID]*Registration),
+		pending: make(map[string]map[types.UID]bool),
+	}
+}
+
+// Has reports whether key is already registered for owner.
+func (r *Registry) Has(key string, owner types.UID) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.items[key][owner]
+	return ok
+}
+
+// TryMarkPending records that an Acquire for key is about to be started on
+// behalf of owner, and reports whether the caller should proceed. It
+// returns false if key is already registered, or already pending, for
+// owner, so that a slow or blocked Acquire call can't be started twice by
+// successive informer resyncs. The caller must call ClearPending if the
+// Acquire it guards does not go on to call Put.
+func (r *Registry) TryMarkPending(key string, owner types.UID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[key][owner]; ok {
+		return false
+	}
+	if r.pending[key] == nil {
+		r.pending[key] = make(map[types.UID]bool)
+	}
+	if r.pending[key][owner] {
+		return false
+	}
+	r.pending[key][owner] = true
+	return true
+}
+
+// ClearPending removes the pending marker set by TryMarkPending, without
+// recording a registration. Call this when the guarded Acquire fails.
+func (r *Registry) ClearPending(key string, owner types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.pending[key], owner)
+	if len(r.pending[key]) == 0 {
+		delete(r.pending, key)
+	}
+}
+
+// Put records a new registration.
+func (r *Registry) Put(key string, owner types.UID, namespace, ownerName string, token *lock.Token) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.items[key] == nil {
+		r.items[key] = make(map[types.UID]*Registration)
+	}
+	r.items[key][owner] = &Registration{
+		Key:       key,
+		Namespace: namespace,
+		Owner:     ownerName,
+		OwnerUID:  owner,
+		Token:     token,
+	}
+
+	delete(r.pending[key], owner)
+	if len(r.pending[key]) == 0 {
+		delete(r.pending, key)
+	}
+}
+
+// Delete removes the registration of key for owner, if any.
+func (r *Registry) Delete(key string, owner types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.items[key], owner)
+	if len(r.items[key]) == 0 {
+		delete(r.items, key)
+	}
+}
+
+// ForOwner returns every registration currently held on behalf of owner.
+func (r *Registry) ForOwner(owner types.UID) []*Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*Registration
+	for _, byOwner := range r.items {
+		if reg, ok := byOwner[owner]; ok {
+			out = append(out, reg)
+		}
+	}
+	return out
+}
+
+// List returns every current registration, for the GET /v1/registrations
+// endpoint.
+func (r *Registry) List() []*Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*Registration
+	for _, byOwner := range r.items {
+		for _, reg := range byOwner {
+			out = append(out, reg)
+		}
+	}
+	return out
+}
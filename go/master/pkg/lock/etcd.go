@@ -0,0 +1,187 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// lockPrefix is the shared etcd key prefix under which every lock managed by
+// this package lives: /dlock/<namespace>/<jobName>/<lockKey>.
+const lockPrefix = "/dlock"
+
+// EtcdLocker is a Locker backend built on etcd v3 sessions and mutexes. Each
+// Acquire binds a lease-backed concurrency.Session to the returned Token, so
+// that the lock is automatically released if the holder process stops
+// renewing the lease (e.g. a network partition).
+type EtcdLocker struct {
+	client    *clientv3.Client
+	namespace string
+	jobName   string
+
+	mu       sync.Mutex
+	sessions map[string]*concurrency.Session
+	mutexes  map[string]*concurrency.Mutex
+}
+
+// NewEtcdLocker dials the given etcd endpoints and returns a Locker backend
+// that stores lock keys under /dlock/<namespace>/<jobName>/.
+func NewEtcdLocker(endpoints []string, namespace, jobName string) (*EtcdLocker, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lock: dial etcd: %w", err)
+	}
+
+	return &EtcdLocker{
+		client:    client,
+		namespace: namespace,
+		jobName:   jobName,
+		sessions:  make(map[string]*concurrency.Session),
+		mutexes:   make(map[string]*concurrency.Mutex),
+	}, nil
+}
+
+func (e *EtcdLocker) keyPath(key string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", lockPrefix, e.namespace, e.jobName, key)
+}
+
+// Acquire implements Locker. It blocks until the mutex for key is locked,
+// ctx is cancelled, or session creation fails.
+func (e *EtcdLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (*Token, <-chan LockLostEvent, error) {
+	session, err := concurrency.NewSession(e.client, concurrency.WithTTL(int(ttl.Seconds())))
+	if err != nil {
+		return nil, nil, fmt.Errorf("lock: new session for %q: %w", key, err)
+	}
+
+	path := e.keyPath(key)
+	mutex := concurrency.NewMutex(session, path)
+	if err := mutex.Lock(ctx); err != nil {
+		session.Close()
+		return nil, nil, fmt.Errorf("lock: acquire %q: %w", key, err)
+	}
+
+	resp, err := e.client.Get(ctx, mutex.Key())
+	if err != nil || len(resp.Kvs) == 0 {
+		mutex.Unlock(ctx)
+		session.Close()
+		return nil, nil, fmt.Errorf("lock: read fencing token for %q: %w", key, err)
+	}
+
+	token := &Token{
+		Key:          key,
+		FencingToken: resp.Kvs[0].ModRevision,
+		Holder:       mutex.Key(),
+	}
+
+	e.mu.Lock()
+	e.sessions[token.Holder] = session
+	e.mutexes[token.Holder] = mutex
+	e.mu.Unlock()
+
+	lostCh := make(chan LockLostEvent, 1)
+	go func() {
+		<-session.Done()
+		e.mu.Lock()
+		_, stillHeld := e.sessions[token.Holder]
+		delete(e.sessions, token.Holder)
+		delete(e.mutexes, token.Holder)
+		e.mu.Unlock()
+
+		if stillHeld {
+			logger.Warnf("lock: session for %q expired, lock lost", key)
+			lostCh <- LockLostEvent{Token: *token, Reason: ReasonSessionExpired}
+		}
+		close(lostCh)
+	}()
+
+	return token, lostCh, nil
+}
+
+// Renew implements Locker. The etcd session already keeps its lease alive in
+// the background, so Renew is a liveness check that the session has not
+// expired out from under the caller.
+func (e *EtcdLocker) Renew(ctx context.Context, token *Token) error {
+	e.mu.Lock()
+	session, ok := e.sessions[token.Holder]
+	e.mu.Unlock()
+	if !ok {
+		return ErrNotHeld
+	}
+
+	select {
+	case <-session.Done():
+		return ErrNotHeld
+	default:
+		return nil
+	}
+}
+
+// Release implements Locker.
+func (e *EtcdLocker) Release(ctx context.Context, token *Token) error {
+	e.mu.Lock()
+	session, ok := e.sessions[token.Holder]
+	mutex := e.mutexes[token.Holder]
+	delete(e.sessions, token.Holder)
+	delete(e.mutexes, token.Holder)
+	e.mu.Unlock()
+	if !ok {
+		return ErrNotHeld
+	}
+
+	if err := mutex.Unlock(ctx); err != nil {
+		return fmt.Errorf("lock: release %q: %w", token.Key, err)
+	}
+	return session.Close()
+}
+
+// Inspect implements Locker.
+func (e *EtcdLocker) Inspect(ctx context.Context, key string) (*Token, error) {
+	resp, err := e.client.Get(ctx, e.keyPath(key), clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortAscend))
+	if err != nil {
+		return nil, fmt.Errorf("lock: inspect %q: %w", key, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, ErrNotFound
+	}
+
+	kv := resp.Kvs[0]
+	return &Token{
+		Key:          key,
+		FencingToken: kv.ModRevision,
+		Holder:       string(kv.Key),
+	}, nil
+}
+
+// Close implements Locker.
+func (e *EtcdLocker) Close() error {
+	e.mu.Lock()
+	for holder, session := range e.sessions {
+		session.Close()
+		delete(e.sessions, holder)
+	}
+	e.mu.Unlock()
+	return e.client.Close()
+}
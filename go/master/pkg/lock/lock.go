@@ -0,0 +1,99 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lock defines the pluggable distributed lock backend used by the
+// master to coordinate exclusive access to a named resource across workers.
+package lock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotHeld is returned when Renew or Release is called with a token that
+// no longer owns the lock, e.g. because the lease expired or another holder
+// has since acquired it.
+var ErrNotHeld = errors.New("lock: token does not hold the lock")
+
+// ErrContended is returned by Acquire when the requested key is already
+// held by someone else.
+var ErrContended = errors.New("lock: key is already held")
+
+// ErrNotFound is returned by Inspect when no holder currently exists for a key.
+var ErrNotFound = errors.New("lock: key has no current holder")
+
+// Token identifies a single, successful acquisition of a lock. It carries a
+// monotonically-increasing FencingToken (derived from the backend's revision
+// or sequence counter) so that downstream resources can reject writes from a
+// holder that has since lost the lock, even if that holder does not yet know
+// it.
+type Token struct {
+	Key          string
+	FencingToken int64
+	Holder       string
+}
+
+// LostReason enumerates why a previously-granted lock was taken away from
+// its holder before an explicit Release.
+type LostReason int
+
+const (
+	// ReasonSessionExpired means the backing session/lease expired, most
+	// commonly because the holder process stopped renewing it (e.g. a
+	// network partition between the holder and the backend).
+	ReasonSessionExpired LostReason = iota
+	// ReasonReleased means Release was called for this token.
+	ReasonReleased
+)
+
+// LockLostEvent is delivered on the channel returned by Acquire when the
+// caller's ownership of the lock ends for any reason other than a
+// successful, caller-initiated Release returning normally.
+type LockLostEvent struct {
+	Token  Token
+	Reason LostReason
+	Err    error
+}
+
+// Locker is the pluggable distributed lock backend. Implementations must be
+// safe for concurrent use by multiple goroutines.
+type Locker interface {
+	// Acquire blocks until the lock identified by key is held, ctx is
+	// cancelled, or an error occurs. ttl bounds how long the lock outlives
+	// its holder once the holder stops keeping it alive; a backend may keep
+	// it alive in the background (the etcd backend's session does). The returned
+	// channel receives exactly one LockLostEvent when ownership ends for any
+	// reason other than a caller-initiated Release, and is closed
+	// afterwards.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (*Token, <-chan LockLostEvent, error)
+
+	// Renew keeps a held lock alive: it restarts the TTL, or, for a backend
+	// that keeps the lock alive itself, checks that it is still held. It
+	// returns ErrNotHeld if token no longer owns the lock.
+	Renew(ctx context.Context, token *Token) error
+
+	// Release gives up ownership of the lock. It returns ErrNotHeld if
+	// token no longer owns the lock.
+	Release(ctx context.Context, token *Token) error
+
+	// Inspect returns the current holder of key, if any. It returns
+	// ErrNotFound if the key is not currently held.
+	Inspect(ctx context.Context, key string) (*Token, error)
+
+	// Close releases any resources held by the backend, such as open
+	// connections. Locks held through this backend are released.
+	Close() error
+}
@@ -0,0 +1,147 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLockerAcquireRelease(t *testing.T) {
+	m := NewMemoryLocker()
+	defer m.Close()
+
+	token, lostCh, err := m.Acquire(context.Background(), "job-a", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if token.FencingToken == 0 {
+		t.Fatalf("expected non-zero fencing token")
+	}
+
+	if _, _, err := m.Acquire(context.Background(), "job-a", time.Minute); err != ErrContended {
+		t.Fatalf("expected second acquire to fail with ErrContended, got %v", err)
+	}
+
+	if err := m.Release(context.Background(), token); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+
+	select {
+	case _, ok := <-lostCh:
+		if ok {
+			t.Fatalf("expected lostCh to be closed without an event after Release")
+		}
+	default:
+		t.Fatalf("expected lostCh to be closed after Release")
+	}
+}
+
+// TestMemoryLockerSessionExpiry simulates a holder that stops renewing its
+// lock, as would happen during a network partition between the holder and
+// the lock backend: the TTL elapses and a LockLostEvent is delivered on the
+// channel returned from Acquire.
+func TestMemoryLockerSessionExpiry(t *testing.T) {
+	m := NewMemoryLocker()
+	defer m.Close()
+
+	token, lostCh, err := m.Acquire(context.Background(), "job-a", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	select {
+	case ev, ok := <-lostCh:
+		if !ok {
+			t.Fatalf("lostCh closed without delivering an event")
+		}
+		if ev.Reason != ReasonSessionExpired {
+			t.Fatalf("expected ReasonSessionExpired, got %v", ev.Reason)
+		}
+		if ev.Token.Holder != token.Holder {
+			t.Fatalf("lost event for wrong holder")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for lock lost event")
+	}
+
+	if _, err := m.Inspect(context.Background(), "job-a"); err != ErrNotFound {
+		t.Fatalf("expected key to be free after expiry, got %v", err)
+	}
+
+	if _, _, err := m.Acquire(context.Background(), "job-a", time.Minute); err != nil {
+		t.Fatalf("expected key to be re-acquirable after expiry: %v", err)
+	}
+}
+
+func TestMemoryLockerRenewExtendsTTL(t *testing.T) {
+	m := NewMemoryLocker()
+	defer m.Close()
+
+	token, lostCh, err := m.Acquire(context.Background(), "job-a", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if err := m.Renew(context.Background(), token); err != nil {
+		t.Fatalf("Renew: %v", err)
+	}
+
+	select {
+	case <-lostCh:
+		t.Fatalf("lock lost despite renewal")
+	case <-time.After(40 * time.Millisecond):
+	}
+}
+
+// TestMemoryLockerRenewUsesOriginalTTL guards against Renew resetting the
+// hold timer to a hardcoded default instead of the TTL the lock was
+// acquired with: a lock acquired with a short TTL must still expire
+// shortly after being renewed, not 30s later.
+func TestMemoryLockerRenewUsesOriginalTTL(t *testing.T) {
+	m := NewMemoryLocker()
+	defer m.Close()
+
+	token, lostCh, err := m.Acquire(context.Background(), "job-a", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := m.Renew(context.Background(), token); err != nil {
+		t.Fatalf("Renew: %v", err)
+	}
+
+	select {
+	case _, ok := <-lostCh:
+		if !ok {
+			t.Fatalf("lostCh closed without delivering an event")
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatalf("lock outlived its original TTL after renewal, Renew is not honoring the acquire-time TTL")
+	}
+}
+
+func TestMemoryLockerInspectNotFound(t *testing.T) {
+	m := NewMemoryLocker()
+	defer m.Close()
+
+	if _, err := m.Inspect(context.Background(), "missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	logger "github.com/sirupsen/logrus"
+)
+
+// acquisitionHistoryLen bounds how many past acquisitions DeadlockMonitor
+// keeps per lock key.
+const acquisitionHistoryLen = 16
+
+// Acquisition records who was granted a lock and when, so a stuck holder can
+// be traced back to the request that created it.
+type Acquisition struct {
+	CallerIP    string    `json:"caller_ip"`
+	JobName     string    `json:"job_name"`
+	GoroutineID uint64    `json:"goroutine_id"`
+	AcquiredAt  time.Time `json:"acquired_at"`
+}
+
+type heldLock struct {
+	current Acquisition
+	history []Acquisition
+	waiters int
+}
+
+// LockStatus is the JSON shape returned by GET /debug/locks.
+type LockStatus struct {
+	Key          string        `json:"key"`
+	HeldSince    time.Time     `json:"held_since"`
+	HeldDuration time.Duration `json:"held_duration_ns"`
+	Waiters      int           `json:"waiters"`
+	Current      Acquisition   `json:"current"`
+	History      []Acquisition `json:"history"`
+}
+
+// DeadlockMonitor tracks how long each lock has been held and how many
+// callers are currently blocked acquiring it, periodically logging a
+// warning for locks that look stuck. It is purely observational: it does
+// not affect whether a lock can be acquired or released.
+type DeadlockMonitor struct {
+	mu    sync.Mutex
+	locks map[string]*heldLock
+
+	holdThreshold time.Duration
+	maxWaiters    int
+}
+
+// NewDeadlockMonitor returns a monitor that warns when a lock is held longer
+// than holdThreshold, or when more than maxWaiters callers are blocked
+// acquiring the same key.
+func NewDeadlockMonitor(holdThreshold time.Duration, maxWaiters int) *DeadlockMonitor {
+	return &DeadlockMonitor{
+		locks:         make(map[string]*heldLock),
+		holdThreshold: holdThreshold,
+		maxWaiters:    maxWaiters,
+	}
+}
+
+// IncWaiting records that a caller has started trying to acquire key.
+func (d *DeadlockMonitor) IncWaiting(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entry(key).waiters++
+}
+
+// DecWaiting records that a caller has stopped trying to acquire key, either
+// because it succeeded or gave up.
+func (d *DeadlockMonitor) DecWaiting(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if entry, ok := d.locks[key]; ok {
+		entry.waiters--
+	}
+}
+
+func (d *DeadlockMonitor) entry(key string) *heldLock {
+	entry, ok := d.locks[key]
+	if !ok {
+		entry = &heldLock{}
+		d.locks[key] = entry
+	}
+	return entry
+}
+
+// RecordAcquire marks key as held as of acq and appends it to the key's
+// acquisition history ring buffer.
+func (d *DeadlockMonitor) RecordAcquire(key string, acq Acquisition) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry := d.entry(key)
+	entry.current = acq
+	entry.history = append(entry.history, acq)
+	if len(entry.history) > acquisitionHistoryLen {
+		entry.history = entry.history[len(entry.history)-acquisitionHistoryLen:]
+	}
+}
+
+// RecordRelease marks key as no longer held.
+func (d *DeadlockMonitor) RecordRelease(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if entry, ok := d.locks[key]; ok {
+		entry.current = Acquisition{}
+	}
+}
+
+// Snapshot returns the current status of every lock key the monitor has
+// ever seen, for GET /debug/locks.
+func (d *DeadlockMonitor) Snapshot() []LockStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]LockStatus, 0, len(d.locks))
+	for key, entry := range d.locks {
+		status := LockStatus{
+			Key:     key,
+			Waiters: entry.waiters,
+			Current: entry.current,
+			History: append([]Acquisition(nil), entry.history...),
+		}
+		if !entry.current.AcquiredAt.IsZero() {
+			status.HeldSince = entry.current.AcquiredAt
+			status.HeldDuration = time.Since(entry.current.AcquiredAt)
+		}
+		out = append(out, status)
+	}
+	return out
+}
+
+// Start runs a ticker that periodically scans every held lock, logging a
+// warning when it has been held longer than holdThreshold or has more than
+// maxWaiters callers queued behind it. It returns once stopCh is closed.
+func (d *DeadlockMonitor) Start(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(d.holdThreshold / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			d.scan()
+		}
+	}
+}
+
+func (d *DeadlockMonitor) scan() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, entry := range d.locks {
+		if !entry.current.AcquiredAt.IsZero() {
+			if held := time.Since(entry.current.AcquiredAt); held > d.holdThreshold {
+				logger.Warnf("deadlock-monitor: lock %q held for %s by job=%s caller=%s goroutine=%d (last %d acquisitions recorded)",
+					key, held, entry.current.JobName, entry.current.CallerIP, entry.current.GoroutineID, len(entry.history))
+			}
+		}
+		if entry.waiters > d.maxWaiters {
+			logger.Warnf("deadlock-monitor: lock %q has %d waiters queued, exceeding threshold %d", key, entry.waiters, d.maxWaiters)
+		}
+	}
+}
+
+// RegisterDebugRoutes mounts GET /debug/locks on router.
+func RegisterDebugRoutes(router *gin.Engine, monitor *DeadlockMonitor) {
+	router.GET("/debug/locks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"locks": monitor.Snapshot()})
+	})
+}
+
+// currentGoroutineID extracts the numeric id from the "goroutine N [...]"
+// header that runtime.Stack prints for the calling goroutine. It is
+// best-effort debug metadata only, not used for any correctness decision.
+func currentGoroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+	id, err := strconv.ParseUint(string(buf), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,68 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadlockMonitorSnapshot(t *testing.T) {
+	m := NewDeadlockMonitor(30*time.Second, 2)
+
+	m.IncWaiting("job-a")
+	m.RecordAcquire("job-a", Acquisition{CallerIP: "10.0.0.1", JobName: "train", AcquiredAt: time.Now()})
+	m.DecWaiting("job-a")
+
+	snapshot := m.Snapshot()
+	if len(snapshot) != 1 {
+		t.Fatalf("expected 1 lock in snapshot, got %d", len(snapshot))
+	}
+	if snapshot[0].Key != "job-a" {
+		t.Fatalf("unexpected key %q", snapshot[0].Key)
+	}
+	if snapshot[0].Waiters != 0 {
+		t.Fatalf("expected waiters to be back to 0, got %d", snapshot[0].Waiters)
+	}
+	if snapshot[0].Current.CallerIP != "10.0.0.1" {
+		t.Fatalf("expected current acquisition to be recorded")
+	}
+	if len(snapshot[0].History) != 1 {
+		t.Fatalf("expected 1 history entry, got %d", len(snapshot[0].History))
+	}
+
+	m.RecordRelease("job-a")
+	snapshot = m.Snapshot()
+	if !snapshot[0].Current.AcquiredAt.IsZero() {
+		t.Fatalf("expected current acquisition to be cleared after release")
+	}
+	if len(snapshot[0].History) != 1 {
+		t.Fatalf("expected release to keep history intact, got %d entries", len(snapshot[0].History))
+	}
+}
+
+func TestDeadlockMonitorHistoryBounded(t *testing.T) {
+	m := NewDeadlockMonitor(30*time.Second, 2)
+
+	for i := 0; i < acquisitionHistoryLen+5; i++ {
+		m.RecordAcquire("job-a", Acquisition{AcquiredAt: time.Now()})
+	}
+
+	snapshot := m.Snapshot()
+	if len(snapshot[0].History) != acquisitionHistoryLen {
+		t.Fatalf("expected history capped at %d, got %d", acquisitionHistoryLen, len(snapshot[0].History))
+	}
+}
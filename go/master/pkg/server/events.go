@@ -0,0 +1,45 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import "time"
+
+// Outcome labels a LockEvent. It is also used as the Prometheus "outcome"
+// label, so values must stay low-cardinality.
+type Outcome string
+
+const (
+	OutcomeAcquired  Outcome = "acquired"
+	OutcomeReleased  Outcome = "released"
+	OutcomeExpired   Outcome = "expired"
+	OutcomeContended Outcome = "contended"
+)
+
+// LockEvent describes a single lock lifecycle transition, emitted by
+// LockHandler to every registered LockEventObserver.
+type LockEvent struct {
+	Namespace string        `json:"namespace"`
+	JobName   string        `json:"job_name"`
+	Key       string        `json:"lock_key"`
+	Outcome   Outcome       `json:"outcome"`
+	Latency   time.Duration `json:"latency_ns"`
+}
+
+// LockEventObserver is notified of every lock lifecycle transition. Both the
+// Prometheus metrics recorder and the webhook registry implement it.
+type LockEventObserver interface {
+	Observe(event LockEvent)
+}
@@ -0,0 +1,262 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	logger "github.com/sirupsen/logrus"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/lock"
+)
+
+// defaultLockTTL is used when a request does not specify one.
+const defaultLockTTL = 30 * time.Second
+
+// LockHandler exposes a lock.Locker backend over HTTP and tracks the tokens
+// handed out to callers so that Release/Renew requests can be matched back
+// to the Acquire that created them. It is the single place that bookkeeps
+// tokens, feeds the DeadlockMonitor, and fans lock lifecycle events out to
+// LockEventObservers, so every API surface mounted on top of it (the legacy
+// /v1/locks routes, the versioned /apis/... routes) shares one view of which
+// locks are held and stays visible to metrics, webhooks, and the deadlock
+// monitor.
+type LockHandler struct {
+	locker    lock.Locker
+	monitor   *DeadlockMonitor
+	namespace string
+	jobName   string
+
+	mu        sync.Mutex
+	tokens    map[string]*lock.Token // lock key -> most recent token
+	observers []LockEventObserver
+}
+
+// NewLockHandler wraps backend for HTTP use. monitor may be nil, in which
+// case no acquire-site metadata is recorded. namespace and jobName label the
+// events reported to any observer registered with AddObserver.
+func NewLockHandler(backend lock.Locker, namespace, jobName string, monitor *DeadlockMonitor) *LockHandler {
+	return &LockHandler{
+		locker:    backend,
+		monitor:   monitor,
+		namespace: namespace,
+		jobName:   jobName,
+		tokens:    make(map[string]*lock.Token),
+	}
+}
+
+// AddObserver registers o to be notified of every lock lifecycle event.
+func (h *LockHandler) AddObserver(o LockEventObserver) {
+	h.observers = append(h.observers, o)
+}
+
+func (h *LockHandler) notify(key string, outcome Outcome, latency time.Duration) {
+	event := LockEvent{Namespace: h.namespace, JobName: h.jobName, Key: key, Outcome: outcome, Latency: latency}
+	for _, o := range h.observers {
+		o.Observe(event)
+	}
+}
+
+// AcquireLock acquires key for ttl, recording it in the shared token store,
+// the deadlock monitor (if enabled) and every registered LockEventObserver.
+// meta is only used for deadlock-monitor bookkeeping; its AcquiredAt and
+// GoroutineID fields are filled in here.
+func (h *LockHandler) AcquireLock(ctx context.Context, key string, ttl time.Duration, meta Acquisition) (*lock.Token, error) {
+	if h.monitor != nil {
+		h.monitor.IncWaiting(key)
+	}
+	start := time.Now()
+	token, lostCh, err := h.locker.Acquire(ctx, key, ttl)
+	latency := time.Since(start)
+	if h.monitor != nil {
+		h.monitor.DecWaiting(key)
+	}
+	if err != nil {
+		h.notify(key, OutcomeContended, latency)
+		return nil, err
+	}
+	h.notify(key, OutcomeAcquired, latency)
+
+	h.mu.Lock()
+	h.tokens[key] = token
+	h.mu.Unlock()
+
+	if h.monitor != nil {
+		meta.AcquiredAt = time.Now()
+		meta.GoroutineID = currentGoroutineID()
+		h.monitor.RecordAcquire(key, meta)
+	}
+
+	go func() {
+		ev, ok := <-lostCh
+		if !ok {
+			return
+		}
+		logger.Warnf("lock %q lost: reason=%v err=%v", ev.Token.Key, ev.Reason, ev.Err)
+		h.mu.Lock()
+		if cur, ok := h.tokens[key]; ok && cur.Holder == ev.Token.Holder {
+			delete(h.tokens, key)
+		}
+		h.mu.Unlock()
+		if h.monitor != nil {
+			h.monitor.RecordRelease(key)
+		}
+		h.notify(key, OutcomeExpired, 0)
+	}()
+
+	return token, nil
+}
+
+// ReleaseLock releases the lock held for key. It returns lock.ErrNotHeld if
+// this LockHandler has no token on record for key.
+func (h *LockHandler) ReleaseLock(ctx context.Context, key string) error {
+	h.mu.Lock()
+	token, ok := h.tokens[key]
+	h.mu.Unlock()
+	if !ok {
+		return lock.ErrNotHeld
+	}
+
+	if err := h.locker.Release(ctx, token); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	delete(h.tokens, key)
+	h.mu.Unlock()
+
+	if h.monitor != nil {
+		h.monitor.RecordRelease(key)
+	}
+	h.notify(key, OutcomeReleased, 0)
+	return nil
+}
+
+// InspectLock returns the current holder of key, if any.
+func (h *LockHandler) InspectLock(ctx context.Context, key string) (*lock.Token, error) {
+	return h.locker.Inspect(ctx, key)
+}
+
+// RenewLock extends the TTL of the lock held for key, keyed off the token
+// this LockHandler has on record for it. It returns lock.ErrNotHeld if this
+// LockHandler has no token on record for key.
+func (h *LockHandler) RenewLock(ctx context.Context, key string) error {
+	h.mu.Lock()
+	token, ok := h.tokens[key]
+	h.mu.Unlock()
+	if !ok {
+		return lock.ErrNotHeld
+	}
+
+	return h.locker.Renew(ctx, token)
+}
+
+// RegisterLockRoutes mounts h's handlers under /v1/locks/:key on router.
+func RegisterLockRoutes(router *gin.Engine, h *LockHandler) {
+	group := router.Group("/v1/locks")
+	group.POST("/:key", h.acquire)
+	group.PUT("/:key", h.renew)
+	group.DELETE("/:key", h.release)
+	group.GET("/:key", h.inspect)
+}
+
+type acquireRequest struct {
+	TTLSeconds int    `json:"ttl_seconds"`
+	JobName    string `json:"job_name"`
+}
+
+type lockResponse struct {
+	Key          string `json:"key"`
+	FencingToken int64  `json:"fencing_token"`
+	Holder       string `json:"holder"`
+}
+
+func toResponse(token *lock.Token) lockResponse {
+	return lockResponse{Key: token.Key, FencingToken: token.FencingToken, Holder: token.Holder}
+}
+
+func (h *LockHandler) acquire(c *gin.Context) {
+	key := c.Param("key")
+
+	var req acquireRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ttl := defaultLockTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	token, err := h.AcquireLock(c.Request.Context(), key, ttl, Acquisition{CallerIP: c.ClientIP(), JobName: req.JobName})
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(token))
+}
+
+func (h *LockHandler) renew(c *gin.Context) {
+	key := c.Param("key")
+
+	if err := h.RenewLock(c.Request.Context(), key); err != nil {
+		if err == lock.ErrNotHeld {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LockHandler) release(c *gin.Context) {
+	key := c.Param("key")
+
+	if err := h.ReleaseLock(c.Request.Context(), key); err != nil {
+		if err == lock.ErrNotHeld {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LockHandler) inspect(c *gin.Context) {
+	key := c.Param("key")
+
+	token, err := h.InspectLock(c.Request.Context(), key)
+	if err == lock.ErrNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toResponse(token))
+}
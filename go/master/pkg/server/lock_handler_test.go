@@ -0,0 +1,63 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/lock"
+)
+
+func TestRenewRouteExtendsHeldLock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewLockHandler(lock.NewMemoryLocker(), "default", "job-a", nil)
+	router := gin.New()
+	RegisterLockRoutes(router, handler)
+
+	acquire := httptest.NewRequest(http.MethodPost, "/v1/locks/job-a", nil)
+	acquireRec := httptest.NewRecorder()
+	router.ServeHTTP(acquireRec, acquire)
+	if acquireRec.Code != http.StatusOK {
+		t.Fatalf("acquire failed: %d %s", acquireRec.Code, acquireRec.Body.String())
+	}
+
+	renew := httptest.NewRequest(http.MethodPut, "/v1/locks/job-a", nil)
+	renewRec := httptest.NewRecorder()
+	router.ServeHTTP(renewRec, renew)
+	if renewRec.Code != http.StatusNoContent {
+		t.Fatalf("renew failed: %d %s", renewRec.Code, renewRec.Body.String())
+	}
+}
+
+func TestRenewRouteOnUnheldKeyReturnsNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewLockHandler(lock.NewMemoryLocker(), "default", "job-a", nil)
+	router := gin.New()
+	RegisterLockRoutes(router, handler)
+
+	renew := httptest.NewRequest(http.MethodPut, "/v1/locks/job-a", nil)
+	renewRec := httptest.NewRecorder()
+	router.ServeHTTP(renewRec, renew)
+	if renewRec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for renew of unheld key, got %d %s", renewRec.Code, renewRec.Body.String())
+	}
+}
@@ -0,0 +1,33 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/controller"
+)
+
+// RegisterRegistrationsRoute mounts GET /v1/registrations, which lists the
+// lock keys the controller has auto-registered from annotated Kubernetes
+// resources.
+func RegisterRegistrationsRoute(router *gin.Engine, registry *controller.Registry) {
+	router.GET("/v1/registrations", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"registrations": registry.List()})
+	})
+}
@@ -0,0 +1,28 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package server wires the master's HTTP API together: the gin router
+// itself plus the handlers registered on it.
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// NewRouter returns a gin.Engine with no routes registered yet. Callers
+// register the handler groups they need, e.g. RegisterLockRoutes.
+func NewRouter() *gin.Engine {
+	return gin.Default()
+}
@@ -0,0 +1,188 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	dlockapi "github.com/intelligent-machine-learning/dlrover/go/master/pkg/api"
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/api/conversion"
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/api/v1alpha1"
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/api/v1beta1"
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/lock"
+)
+
+// APIGroup is the group served under /apis/<APIGroup>/<version>/....
+const APIGroup = "dlock.dlrover.io"
+
+// VersionedAPI serves the /apis/dlock.dlrover.io/<version>/locks/:key
+// routes. It dispatches on the path version and converts each version's
+// wire types to and from the internal api.Lock hub via the conversion
+// package, but delegates every acquire/release/inspect to the same
+// LockHandler the legacy /v1/locks routes use, so a lock taken through one
+// API surface can be released through the other, and both stay visible to
+// the deadlock monitor, metrics, and webhooks.
+type VersionedAPI struct {
+	handler *LockHandler
+	served  map[string]bool
+}
+
+// NewVersionedAPI returns a VersionedAPI serving only the versions named in
+// servedVersions (e.g. from -served-versions). An unknown version name is
+// ignored; RegisterRoutes only ever mounts versions this package knows
+// about. handler is the same LockHandler registered for /v1/locks.
+func NewVersionedAPI(handler *LockHandler, servedVersions []string) *VersionedAPI {
+	served := make(map[string]bool, len(servedVersions))
+	for _, v := range servedVersions {
+		served[v] = true
+	}
+	return &VersionedAPI{handler: handler, served: served}
+}
+
+// resourceKind describes one kind served by a version, for GET /apis.
+type resourceKind struct {
+	Kind     string `json:"kind"`
+	Resource string `json:"resource"`
+}
+
+type servedVersion struct {
+	GroupVersion string         `json:"groupVersion"`
+	Kinds        []resourceKind `json:"kinds"`
+}
+
+// RegisterRoutes mounts every served version's routes on router, plus the
+// GET /apis discovery endpoint listing them.
+func (v *VersionedAPI) RegisterRoutes(router *gin.Engine) {
+	var discovery []servedVersion
+
+	if v.served[v1alpha1.GroupVersion] {
+		v.mountVersion(router, v1alpha1.GroupVersion,
+			func() conversion.Convertible { return &v1alpha1.AcquireRequest{} },
+			func() conversion.Convertible { return &v1alpha1.LockResponse{} },
+		)
+		discovery = append(discovery, servedVersion{
+			GroupVersion: APIGroup + "/" + v1alpha1.GroupVersion,
+			Kinds:        []resourceKind{{Kind: "Lock", Resource: "locks"}},
+		})
+	}
+	if v.served[v1beta1.GroupVersion] {
+		v.mountVersion(router, v1beta1.GroupVersion,
+			func() conversion.Convertible { return &v1beta1.AcquireRequest{} },
+			func() conversion.Convertible { return &v1beta1.LockResponse{} },
+		)
+		discovery = append(discovery, servedVersion{
+			GroupVersion: APIGroup + "/" + v1beta1.GroupVersion,
+			Kinds:        []resourceKind{{Kind: "Lock", Resource: "locks"}},
+		})
+	}
+
+	router.GET("/apis", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"servedVersions": discovery})
+	})
+}
+
+func (v *VersionedAPI) mountVersion(
+	router *gin.Engine,
+	version string,
+	newRequest func() conversion.Convertible,
+	newResponse func() conversion.Convertible,
+) {
+	group := router.Group("/apis/" + APIGroup + "/" + version)
+	group.POST("/locks/:key", v.acquireHandler(newRequest, newResponse))
+	group.DELETE("/locks/:key", v.releaseHandler())
+	group.GET("/locks/:key", v.inspectHandler(newResponse))
+}
+
+func (v *VersionedAPI) acquireHandler(newRequest, newResponse func() conversion.Convertible) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.Param("key")
+
+		req := newRequest()
+		if err := c.ShouldBindJSON(req); err != nil && err != io.EOF {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		hub, err := conversion.ToHub(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ttl := hub.TTL
+		if ttl <= 0 {
+			ttl = defaultLockTTL
+		}
+
+		token, err := v.handler.AcquireLock(c.Request.Context(), key, ttl, Acquisition{CallerIP: c.ClientIP(), JobName: hub.JobName})
+		if err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+
+		resp := newResponse()
+		if err := conversion.FromHub(tokenToHub(token), resp); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (v *VersionedAPI) releaseHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.Param("key")
+
+		if err := v.handler.ReleaseLock(c.Request.Context(), key); err != nil {
+			if err == lock.ErrNotHeld {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (v *VersionedAPI) inspectHandler(newResponse func() conversion.Convertible) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.Param("key")
+
+		token, err := v.handler.InspectLock(c.Request.Context(), key)
+		if err == lock.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		resp := newResponse()
+		if err := conversion.FromHub(tokenToHub(token), resp); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func tokenToHub(token *lock.Token) *dlockapi.Lock {
+	return &dlockapi.Lock{Key: token.Key, FencingToken: token.FencingToken, Holder: token.Holder}
+}
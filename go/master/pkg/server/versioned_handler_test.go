@@ -0,0 +1,52 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/intelligent-machine-learning/dlrover/go/master/pkg/lock"
+)
+
+func TestVersionedAPISharesLockHandlerState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewLockHandler(lock.NewMemoryLocker(), "default", "job-a", nil)
+	router := gin.New()
+	RegisterLockRoutes(router, handler)
+	NewVersionedAPI(handler, []string{"v1alpha1"}).RegisterRoutes(router)
+
+	acquire := httptest.NewRequest(http.MethodPost, "/apis/dlock.dlrover.io/v1alpha1/locks/job-a", nil)
+	acquireRec := httptest.NewRecorder()
+	router.ServeHTTP(acquireRec, acquire)
+	if acquireRec.Code != http.StatusOK {
+		t.Fatalf("acquire via v1alpha1 failed: %d %s", acquireRec.Code, acquireRec.Body.String())
+	}
+
+	// The lock was acquired through the versioned API; releasing it through
+	// the legacy /v1/locks surface must succeed because both share the same
+	// LockHandler token store.
+	release := httptest.NewRequest(http.MethodDelete, "/v1/locks/job-a", nil)
+	releaseRec := httptest.NewRecorder()
+	router.ServeHTTP(releaseRec, release)
+	if releaseRec.Code != http.StatusNoContent {
+		t.Fatalf("release via legacy /v1/locks failed: %d %s", releaseRec.Code, releaseRec.Body.String())
+	}
+}
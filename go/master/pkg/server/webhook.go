@@ -0,0 +1,127 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	logger "github.com/sirupsen/logrus"
+)
+
+// webhookTimeout bounds how long fire waits for a single webhook delivery,
+// so a slow or unreachable endpoint can't leak a goroutine and socket per
+// lock lifecycle event.
+const webhookTimeout = 5 * time.Second
+
+// Webhook is an operator-registered outbound callback, fired whenever a
+// lock lifecycle event it subscribes to occurs.
+type Webhook struct {
+	Name   string    `json:"name"`
+	URL    string    `json:"url"`
+	Events []Outcome `json:"events"`
+}
+
+func (w *Webhook) wants(outcome Outcome) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, o := range w.Events {
+		if o == outcome {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookRegistry is a LockEventObserver that POSTs a JSON payload to every
+// registered webhook subscribed to a lock event's outcome.
+type WebhookRegistry struct {
+	client *http.Client
+
+	mu    sync.RWMutex
+	hooks map[string]*Webhook
+}
+
+// NewWebhookRegistry returns an empty WebhookRegistry.
+func NewWebhookRegistry() *WebhookRegistry {
+	return &WebhookRegistry{
+		client: &http.Client{Timeout: webhookTimeout},
+		hooks:  make(map[string]*Webhook),
+	}
+}
+
+// Register adds or replaces the webhook named hook.Name.
+func (r *WebhookRegistry) Register(hook Webhook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks[hook.Name] = &hook
+}
+
+// Observe implements LockEventObserver, firing matching webhooks
+// asynchronously so a slow or unreachable endpoint never blocks the lock
+// request path.
+func (r *WebhookRegistry) Observe(event LockEvent) {
+	r.mu.RLock()
+	targets := make([]*Webhook, 0, len(r.hooks))
+	for _, hook := range r.hooks {
+		if hook.wants(event.Outcome) {
+			targets = append(targets, hook)
+		}
+	}
+	r.mu.RUnlock()
+
+	for _, hook := range targets {
+		go r.fire(hook, event)
+	}
+}
+
+func (r *WebhookRegistry) fire(hook *Webhook, event LockEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		logger.Warnf("webhook %q: failed to encode event: %v", hook.Name, err)
+		return
+	}
+
+	resp, err := r.client.Post(hook.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		logger.Warnf("webhook %q: delivery to %s failed: %v", hook.Name, hook.URL, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		logger.Warnf("webhook %q: delivery to %s returned status %d", hook.Name, hook.URL, resp.StatusCode)
+	}
+}
+
+// RegisterWebhookRoutes mounts POST /v1/webhooks/:name on router.
+func RegisterWebhookRoutes(router *gin.Engine, registry *WebhookRegistry) {
+	router.POST("/v1/webhooks/:name", func(c *gin.Context) {
+		var hook Webhook
+		if err := c.ShouldBindJSON(&hook); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		hook.Name = c.Param("name")
+		registry.Register(hook)
+		c.Status(http.StatusNoContent)
+	})
+}
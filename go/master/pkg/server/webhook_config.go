@@ -0,0 +1,38 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadWebhookConfig reads a JSON array of Webhook entries from path, for
+// pre-registering outbound callbacks at startup via -webhook-config. Use
+// POST /v1/webhooks/:name to register additional ones at runtime.
+func LoadWebhookConfig(path string) ([]Webhook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("server: read webhook config %q: %w", path, err)
+	}
+
+	var hooks []Webhook
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return nil, fmt.Errorf("server: parse webhook config %q: %w", path, err)
+	}
+	return hooks, nil
+}
@@ -0,0 +1,35 @@
+/*
+DLlock Project (a) 2024 Leyi Ye
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import "testing"
+
+func TestWebhookWantsAllEventsWhenUnfiltered(t *testing.T) {
+	hook := Webhook{Name: "all"}
+	if !hook.wants(OutcomeAcquired) || !hook.wants(OutcomeExpired) {
+		t.Fatalf("expected a webhook with no Events filter to match every outcome")
+	}
+}
+
+func TestWebhookWantsFiltersByOutcome(t *testing.T) {
+	hook := Webhook{Name: "expiry-only", Events: []Outcome{OutcomeExpired}}
+	if hook.wants(OutcomeAcquired) {
+		t.Fatalf("expected filtered webhook to reject OutcomeAcquired")
+	}
+	if !hook.wants(OutcomeExpired) {
+		t.Fatalf("expected filtered webhook to match OutcomeExpired")
+	}
+}